@@ -0,0 +1,146 @@
+// Package cache memoizes providers.Provider lookups. Each wrapped
+// provider gets its own TTL-bound entry per location, collapses
+// concurrent identical lookups via singleflight, and keeps serving a
+// stale entry immediately while it refreshes in the background, so a
+// slow or erroring upstream never blocks a request that would otherwise
+// be a cache hit.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/grggls/go-hello/providers"
+)
+
+// DefaultTTL is how long an entry is considered fresh when the caller
+// doesn't configure one explicitly.
+const DefaultTTL = 10 * time.Minute
+
+// refreshTimeout bounds a background stale-while-revalidate refresh,
+// which runs detached from the request that triggered it.
+const refreshTimeout = 10 * time.Second
+
+// entry is one cached Observation and when it stops being fresh.
+type entry struct {
+	obs       providers.Observation
+	expiresAt time.Time
+}
+
+// Provider wraps a providers.Provider with a TTL cache. It implements
+// providers.Provider itself, so it can be passed anywhere the unwrapped
+// provider could be.
+//
+// Note that the request's units selection isn't part of the cache key:
+// providers.Provider always returns canonical SI values, and conversion
+// to the caller's requested units happens afterward via
+// providers.Observation.In, so it can't go stale.
+type Provider struct {
+	name     string
+	upstream providers.Provider
+	ttl      time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]entry
+
+	group singleflight.Group
+
+	hits, misses, staleServes, upstreamErrors uint64
+}
+
+// New wraps upstream in a cache keyed under name (used in cache keys and
+// Stats), refreshing entries no more often than ttl.
+func New(name string, upstream providers.Provider, ttl time.Duration) *Provider {
+	return &Provider{
+		name:     name,
+		upstream: upstream,
+		ttl:      ttl,
+		entries:  map[string]entry{},
+	}
+}
+
+// Observe returns a cached Observation if one is fresh, kicks off an
+// async refresh and returns the stale value if one exists but expired,
+// or fetches from upstream (collapsing concurrent identical requests)
+// on a full miss.
+func (c *Provider) Observe(ctx context.Context, req providers.Request) (providers.Observation, error) {
+	key := cacheKey(req)
+	now := time.Now()
+
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if ok && now.Before(e.expiresAt) {
+		atomic.AddUint64(&c.hits, 1)
+		return e.obs, nil
+	}
+
+	if ok {
+		atomic.AddUint64(&c.staleServes, 1)
+		c.refreshAsync(key, req)
+		return e.obs, nil
+	}
+
+	atomic.AddUint64(&c.misses, 1)
+
+	obs, err := c.fetch(ctx, key, req)
+	if err != nil {
+		atomic.AddUint64(&c.upstreamErrors, 1)
+		return providers.Observation{}, err
+	}
+
+	return obs, nil
+}
+
+// fetch calls the upstream provider, collapsing concurrent calls for the
+// same key into one, and stores the result.
+func (c *Provider) fetch(ctx context.Context, key string, req providers.Request) (providers.Observation, error) {
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		obs, err := c.upstream.Observe(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.entries[key] = entry{obs: obs, expiresAt: time.Now().Add(c.ttl)}
+		c.mu.Unlock()
+
+		return obs, nil
+	})
+	if err != nil {
+		return providers.Observation{}, err
+	}
+
+	return v.(providers.Observation), nil
+}
+
+// refreshAsync fetches key in the background, detached from the request
+// that found it stale. singleflight still collapses this with any other
+// in-flight refresh for the same key.
+func (c *Provider) refreshAsync(key string, req providers.Request) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+
+		if _, err := c.fetch(ctx, key, req); err != nil {
+			atomic.AddUint64(&c.upstreamErrors, 1)
+			log.Printf("cache: stale-while-revalidate refresh of %s failed: %v", key, err)
+		}
+	}()
+}
+
+// cacheKey normalizes req into a string unique per (provider, location).
+func cacheKey(req providers.Request) string {
+	if req.HasCoord() {
+		return fmt.Sprintf("%.4f,%.4f", *req.Lat, *req.Lon)
+	}
+	return strings.ToLower(strings.TrimSpace(req.City))
+}
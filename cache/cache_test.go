@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grggls/go-hello/providers"
+	"github.com/grggls/go-hello/units"
+)
+
+// countingProvider counts how many times Observe actually ran upstream,
+// returning an incrementing temperature each time so tests can tell
+// fresh reads apart from cached ones.
+type countingProvider struct {
+	calls int64
+	delay time.Duration
+}
+
+func (p *countingProvider) Observe(ctx context.Context, req providers.Request) (providers.Observation, error) {
+	if p.delay > 0 {
+		time.Sleep(p.delay)
+	}
+	n := atomic.AddInt64(&p.calls, 1)
+	return providers.Observation{Temp: units.NewTemperature(float64(n), units.Kelvin)}, nil
+}
+
+func TestCacheHitWithinTTL(t *testing.T) {
+	upstream := &countingProvider{}
+	c := New("test", upstream, time.Minute)
+
+	ctx := context.Background()
+	req := providers.Request{City: "London"}
+
+	first, err := c.Observe(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := c.Observe(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Temp.Kelvin() != second.Temp.Kelvin() {
+		t.Fatalf("expected a cached result, got two different observations: %v, %v", first, second)
+	}
+	if upstream.calls != 1 {
+		t.Fatalf("upstream called %d times, want 1", upstream.calls)
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("stats = %+v, want 1 hit and 1 miss", stats)
+	}
+}
+
+func TestCacheMissAfterTTL(t *testing.T) {
+	upstream := &countingProvider{}
+	c := New("test", upstream, time.Millisecond)
+
+	ctx := context.Background()
+	req := providers.Request{City: "London"}
+
+	if _, err := c.Observe(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	// The entry is stale now, not gone, so this still serves it
+	// immediately and triggers a refresh in the background.
+	if _, err := c.Observe(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt64(&upstream.calls) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := atomic.LoadInt64(&upstream.calls); got < 2 {
+		t.Fatalf("upstream called %d times, want at least 2 after the stale refresh", got)
+	}
+
+	stats := c.Stats()
+	if stats.StaleServes != 1 {
+		t.Fatalf("stats.StaleServes = %d, want 1", stats.StaleServes)
+	}
+}
+
+func TestCacheCollapsesConcurrentMisses(t *testing.T) {
+	upstream := &countingProvider{delay: 20 * time.Millisecond}
+	c := New("test", upstream, time.Minute)
+
+	req := providers.Request{City: "London"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Observe(context.Background(), req); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if upstream.calls != 1 {
+		t.Fatalf("upstream called %d times, want 1 (singleflight should collapse concurrent misses)", upstream.calls)
+	}
+}
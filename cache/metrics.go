@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of one cache.Provider's counters.
+type Stats struct {
+	Name           string
+	Hits           uint64
+	Misses         uint64
+	StaleServes    uint64
+	UpstreamErrors uint64
+}
+
+// Stats snapshots c's counters.
+func (c *Provider) Stats() Stats {
+	return Stats{
+		Name:           c.name,
+		Hits:           atomic.LoadUint64(&c.hits),
+		Misses:         atomic.LoadUint64(&c.misses),
+		StaleServes:    atomic.LoadUint64(&c.staleServes),
+		UpstreamErrors: atomic.LoadUint64(&c.upstreamErrors),
+	}
+}
+
+// metric is one Prometheus gauge family this package exposes.
+type metric struct {
+	name  string
+	help  string
+	value func(Stats) uint64
+}
+
+var metrics = []metric{
+	{"go_hello_cache_hits_total", "Cache hits, per provider.", func(s Stats) uint64 { return s.Hits }},
+	{"go_hello_cache_misses_total", "Cache misses, per provider.", func(s Stats) uint64 { return s.Misses }},
+	{"go_hello_cache_stale_serves_total", "Stale entries served while revalidating, per provider.", func(s Stats) uint64 { return s.StaleServes }},
+	{"go_hello_cache_upstream_errors_total", "Upstream errors seen while filling or refreshing the cache, per provider.", func(s Stats) uint64 { return s.UpstreamErrors }},
+}
+
+// WritePrometheus renders caches' Stats in Prometheus text exposition
+// format, one gauge family per counter with a `provider` label per
+// cache.Provider.
+func WritePrometheus(w io.Writer, caches []*Provider) error {
+	stats := make([]Stats, len(caches))
+	for i, c := range caches {
+		stats[i] = c.Stats()
+	}
+
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", m.name, m.help, m.name); err != nil {
+			return err
+		}
+
+		for _, s := range stats {
+			if _, err := fmt.Fprintf(w, "%s{provider=%q} %d\n", m.name, s.Name, m.value(s)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
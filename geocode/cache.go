@@ -0,0 +1,75 @@
+package geocode
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultCacheTTL is how long a geocoding result is kept: places move
+// far less often than their weather does, so this is deliberately much
+// longer than a weather cache's TTL.
+const DefaultCacheTTL = 24 * time.Hour
+
+type cacheEntry struct {
+	loc       Location
+	expiresAt time.Time
+}
+
+// cachingGeocoder wraps a Geocoder with a TTL cache, collapsing
+// concurrent lookups of the same query via singleflight. Unlike the
+// weather cache in the cache package, a stale geocoding result isn't
+// worth serving early -- places don't change fast enough for that to
+// matter -- so this is a plain TTL cache.
+type cachingGeocoder struct {
+	upstream Geocoder
+	ttl      time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]cacheEntry
+
+	group singleflight.Group
+}
+
+// WithCache wraps upstream in a TTL cache keyed by the normalized query
+// string.
+func WithCache(upstream Geocoder, ttl time.Duration) Geocoder {
+	return &cachingGeocoder{
+		upstream: upstream,
+		ttl:      ttl,
+		entries:  map[string]cacheEntry{},
+	}
+}
+
+func (c *cachingGeocoder) Geocode(ctx context.Context, query string) (Location, error) {
+	key := strings.ToLower(strings.TrimSpace(query))
+
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if ok && time.Now().Before(e.expiresAt) {
+		return e.loc, nil
+	}
+
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		loc, err := c.upstream.Geocode(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.entries[key] = cacheEntry{loc: loc, expiresAt: time.Now().Add(c.ttl)}
+		c.mu.Unlock()
+
+		return loc, nil
+	})
+	if err != nil {
+		return Location{}, err
+	}
+
+	return v.(Location), nil
+}
@@ -0,0 +1,64 @@
+package geocode
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingGeocoder counts how many times Geocode actually ran upstream.
+type countingGeocoder struct {
+	calls int64
+	delay time.Duration
+}
+
+func (g *countingGeocoder) Geocode(ctx context.Context, query string) (Location, error) {
+	if g.delay > 0 {
+		time.Sleep(g.delay)
+	}
+	atomic.AddInt64(&g.calls, 1)
+	return Location{Name: query, Lat: 1, Lon: 2}, nil
+}
+
+func TestCachingGeocoderHitsWithinTTL(t *testing.T) {
+	upstream := &countingGeocoder{}
+	g := WithCache(upstream, time.Minute)
+
+	if _, err := g.Geocode(context.Background(), "London"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Geocode(context.Background(), "London"); err != nil {
+		t.Fatal(err)
+	}
+
+	if upstream.calls != 1 {
+		t.Fatalf("upstream called %d times, want 1", upstream.calls)
+	}
+}
+
+func TestCachingGeocoderCollapsesConcurrentLookups(t *testing.T) {
+	// The delay gives every goroutine a chance to reach Do before the
+	// first call returns and singleflight evicts its in-flight entry --
+	// without it, a fast upstream can let calls race past each other and
+	// the "called exactly once" assertion below flakes.
+	upstream := &countingGeocoder{delay: 20 * time.Millisecond}
+	g := WithCache(upstream, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := g.Geocode(context.Background(), "London"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if upstream.calls != 1 {
+		t.Fatalf("upstream called %d times, want 1", upstream.calls)
+	}
+}
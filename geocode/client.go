@@ -0,0 +1,22 @@
+package geocode
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// httpClient returns client if set, falling back to http.DefaultClient.
+func httpClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return http.DefaultClient
+}
+
+// checkStatus turns a non-200 response into an error.
+func checkStatus(provider string, resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	return fmt.Errorf("%s: unexpected status %s", provider, resp.Status)
+}
@@ -0,0 +1,52 @@
+// Package geocode resolves free-form place names and "@lat,lon" strings
+// into a canonical Location, so /weather/ can accept "New York", "New
+// York, NY, US" or raw coordinates and providers always see a resolved
+// place.
+package geocode
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Location is a resolved place: a canonical name, its country, and its
+// coordinates.
+type Location struct {
+	Name    string  `json:"name"`
+	Country string  `json:"country"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+}
+
+// Geocoder resolves a free-form query into a Location.
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (Location, error)
+}
+
+// ParseCoordinates recognizes the "@lat,lon" form of a /weather/ query,
+// e.g. "@40.7128,-74.0060", letting a caller skip geocoding entirely
+// when they already have coordinates. The second return value is false
+// for anything else.
+func ParseCoordinates(query string) (Location, bool) {
+	if !strings.HasPrefix(query, "@") {
+		return Location{}, false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(query, "@"), ",", 2)
+	if len(parts) != 2 {
+		return Location{}, false
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return Location{}, false
+	}
+
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return Location{}, false
+	}
+
+	return Location{Name: query, Lat: lat, Lon: lon}, true
+}
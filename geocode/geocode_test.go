@@ -0,0 +1,33 @@
+package geocode
+
+import "testing"
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantOK  bool
+		wantLat float64
+		wantLon float64
+	}{
+		{"@40.7128,-74.0060", true, 40.7128, -74.0060},
+		{"@ 40.7128 , -74.0060 ", true, 40.7128, -74.0060},
+		{"New York", false, 0, 0},
+		{"@not,coords", false, 0, 0},
+		{"@40.7128", false, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			loc, ok := ParseCoordinates(tt.query)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseCoordinates(%q) ok = %v, want %v", tt.query, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if loc.Lat != tt.wantLat || loc.Lon != tt.wantLon {
+				t.Fatalf("ParseCoordinates(%q) = %+v, want lat=%v lon=%v", tt.query, loc, tt.wantLat, tt.wantLon)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// openWeatherMap geocodes via OpenWeatherMap's Geocoding API.
+// https://openweathermap.org/api/geocoding-api
+type openWeatherMap struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewOpenWeatherMap builds the default Geocoder, backed by
+// OpenWeatherMap's /geo/1.0/direct endpoint.
+func NewOpenWeatherMap(apiKey string) Geocoder {
+	return openWeatherMap{apiKey: apiKey}
+}
+
+func (g openWeatherMap) Geocode(ctx context.Context, query string) (Location, error) {
+	reqURL := "http://api.openweathermap.org/geo/1.0/direct?limit=1&appid=" + g.apiKey + "&q=" + url.QueryEscape(query)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return Location{}, err
+	}
+
+	resp, err := httpClient(g.client).Do(req)
+	if err != nil {
+		return Location{}, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus("geocode", resp); err != nil {
+		return Location{}, err
+	}
+
+	locs, err := ParseOWMResponse(resp.Body)
+	if err != nil {
+		return Location{}, err
+	}
+	if len(locs) == 0 {
+		return Location{}, fmt.Errorf("geocode: no results for %q", query)
+	}
+
+	return locs[0], nil
+}
+
+// ParseOWMResponse decodes an OpenWeatherMap geocoding JSON response
+// (a list of matches, best first) into Locations.
+func ParseOWMResponse(r io.Reader) ([]Location, error) {
+	var raw []struct {
+		Name    string  `json:"name"`
+		Country string  `json:"country"`
+		Lat     float64 `json:"lat"`
+		Lon     float64 `json:"lon"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	locs := make([]Location, len(raw))
+	for i, m := range raw {
+		locs[i] = Location{Name: m.Name, Country: m.Country, Lat: m.Lat, Lon: m.Lon}
+	}
+
+	return locs, nil
+}
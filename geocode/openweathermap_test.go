@@ -0,0 +1,45 @@
+package geocode
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseOWMResponse(t *testing.T) {
+	data, err := os.ReadFile("testdata/owm.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locs, err := ParseOWMResponse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseOWMResponse: %v", err)
+	}
+
+	if len(locs) != 1 {
+		t.Fatalf("len(locs) = %d, want 1", len(locs))
+	}
+
+	want := Location{Name: "London", Country: "GB", Lat: 51.5085, Lon: -0.1257}
+	if locs[0] != want {
+		t.Errorf("locs[0] = %+v, want %+v", locs[0], want)
+	}
+}
+
+func TestParseOWMResponseMalformed(t *testing.T) {
+	if _, err := ParseOWMResponse(strings.NewReader("{not valid json")); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func TestParseOWMResponseEmpty(t *testing.T) {
+	locs, err := ParseOWMResponse(strings.NewReader("[]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locs) != 0 {
+		t.Fatalf("len(locs) = %d, want 0", len(locs))
+	}
+}
@@ -3,27 +3,115 @@ package main
 
 // import pulls in an external module
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/grggls/go-hello/cache"
+	"github.com/grggls/go-hello/geocode"
+	"github.com/grggls/go-hello/providers"
+	"github.com/grggls/go-hello/units"
 )
 
+// defaultTimeout bounds how long we wait on providers when the caller
+// doesn't supply a `?timeout=` query parameter.
+const defaultTimeout = 5 * time.Second
+
 // main() reserved func name for executable packages -- entrypoint
 func main() {
-	mw := multiWeatherProvider{
-		openWeatherMap{},
-		weatherUnderground{apiKey: "your-key-here"},
+	cfg, err := providers.LoadConfig(os.Getenv("WEATHER_CONFIG"))
+	if err != nil {
+		log.Fatalf("loading provider config: %v", err)
+	}
+
+	named, err := providers.Build(cfg)
+	if err != nil {
+		log.Fatalf("building providers: %v", err)
+	}
+	if len(named) == 0 {
+		log.Fatalf("no providers enabled -- set WEATHER_PROVIDERS or WEATHER_CONFIG")
+	}
+
+	ttl := cache.DefaultTTL
+	if raw := os.Getenv("WEATHER_CACHE_TTL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil {
+			ttl = d
+		}
+	}
+
+	// quorum is the default minimum number of providers that must reply
+	// before we're willing to answer the caller. cfg.Quorum (WEATHER_QUORUM)
+	// overrides it; with neither set we require every enabled provider,
+	// since that's the only default that actually exercises the merge in
+	// mergeObservations instead of returning whichever provider is fastest.
+	quorum := cfg.Quorum
+	if quorum <= 0 {
+		quorum = len(named)
 	}
 
+	caches := make([]*cache.Provider, 0, len(named))
+	mw := multiWeatherProvider{quorum: quorum}
+	for _, n := range named {
+		cached := cache.New(n.Name, n.Provider, ttl)
+		caches = append(caches, cached)
+		mw.providers = append(mw.providers, cached)
+	}
+
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		cache.WritePrometheus(w, caches)
+	})
+
+	geocoder := geocode.WithCache(geocode.NewOpenWeatherMap(os.Getenv("OWM_API_KEY")), geocode.DefaultCacheTTL)
+
 	// define this handler func inline
 	http.HandleFunc("/weather/", func(w http.ResponseWriter, r *http.Request) {
 		begin := time.Now()
-		// strings.SplitN takes everything in the path after '/weather/' and puts it in 'city' ``
-		city := strings.SplitN(r.URL.Path, "/", 3)[2]
+		// strings.SplitN takes everything in the path after '/weather/' and puts it in 'query' ``
+		query := strings.SplitN(r.URL.Path, "/", 3)[2]
+
+		system, err := units.ParseSystem(r.URL.Query().Get("units"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		timeout := defaultTimeout
+		if raw := r.URL.Query().Get("timeout"); raw != "" {
+			if d, err := time.ParseDuration(raw); err == nil {
+				timeout = d
+			}
+		}
+
+		quorum := mw.quorum
+		if raw := r.URL.Query().Get("quorum"); raw != "" {
+			if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+				quorum = n
+			}
+		}
+
+		// r.Context() is canceled if the client disconnects; wrapping it
+		// with a deadline also bounds how long we'll wait on providers.
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		loc, ok := geocode.ParseCoordinates(query)
+		if !ok {
+			loc, err = geocoder.Geocode(ctx, query)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
 
-		temp, err := mw.temperature(city)
+		obs, err := mw.Observe(ctx, loc, quorum)
 		// if there's an error calling query, propogate that error vi http.Error
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -32,11 +120,10 @@ func main() {
 
 		// query was successful. tell the client we're returning json data
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
-		// use json.NewEncoder to JSON-encode the weatherData directly
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"city": city,
-			"temp": temp,
-			"took": time.Since(begin).String(),
+		json.NewEncoder(w).Encode(weatherResponse{
+			Location:    loc,
+			Observation: obs.In(system),
+			Took:        time.Since(begin).String(),
 		})
 	})
 
@@ -49,117 +136,203 @@ func hello(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("hello!"))
 }
 
-/*
- * define a new type with "type" keyword
- * declare it a struct
- * each field gets a name (Name, Main, Kelvin)
- * and a type (string, float64, an inline struct [called Main])
- * the `json:"foo"` bits are called 'tags' and they're metadata or attributes
- *   they allow us to use the encoding/json package to unmarshall the API's
- *   responses, giving us the benefits of type safety when using a 3rd party API response
- */
-type weatherData struct {
-	Name string `json:"name"`
-	Main struct {
-		Kelvin float64 `json:"temp"`
-	} `json:"main"`
+// weatherResponse is what /weather/ sends back: the resolved location,
+// the merged Observation fields (embedded so they marshal inline), and
+// how long the request took.
+type weatherResponse struct {
+	geocode.Location
+	providers.Observation
+	Took string `json:"took"`
 }
 
-type weatherProvider interface {
-	temperature(city string) (float64, error) // in Kelvin, s'il vous plait
+// multiWeatherProvider fans out to all of its providers concurrently and
+// merges whatever replies arrive before ctx is done.
+type multiWeatherProvider struct {
+	providers []providers.Provider
+	// quorum is the default minimum number of providers that must
+	// succeed for Observe to return a result instead of an error, used
+	// whenever a caller doesn't ask Observe for a different one.
+	quorum int
 }
 
-type openWeatherMap struct{}
+// Observe fans out to every provider and waits for either quorum of
+// them to succeed or ctx to be done, whichever comes first. quorum
+// overrides w.quorum for this call alone -- a non-positive value means
+// "use w.quorum" -- so callers like the /weather/ handler can honor a
+// per-request `?quorum=` override without sharing mutable state.
+func (w multiWeatherProvider) Observe(ctx context.Context, loc geocode.Location, quorum int) (providers.Observation, error) {
+	if quorum <= 0 {
+		quorum = w.quorum
+	}
 
-// takes a string representing the city, and returns a weatherData struct and an error
-func (w openWeatherMap) temperature(city string) (float64, error) {
-	// fetch weather data from openweathermap using our 'city' string and the api key we requested
-	Apikey := "28d2fc80b71bd20d670acf2326ad0b84"
-	resp, err := http.Get("http://api.openweathermap.org/data/2.5/weather?APPID=" + Apikey + "&q=" + city)
-	if err != nil {
-		return 0, err
+	type result struct {
+		obs providers.Observation
+		err error
+	}
+
+	req := providers.Request{City: loc.Name, Lat: &loc.Lat, Lon: &loc.Lon}
+	results := make(chan result, len(w.providers))
+
+	for _, provider := range w.providers {
+		go func(p providers.Provider) {
+			obs, err := p.Observe(ctx, req)
+			results <- result{obs, err}
+		}(provider)
 	}
 
-	// resource  mgmt - if the http.Get has succeeded, defer a call to close the response Body
-	defer resp.Body.Close()
+	var (
+		obs  []providers.Observation
+		errs []error
+	)
+
+collect:
+	for received := 0; received < len(w.providers); received++ {
+		select {
+		case res := <-results:
+			if res.err != nil {
+				errs = append(errs, res.err)
+				continue
+			}
 
-	// define the response struct inline
-	var d struct {
-		Main struct {
-			Kelvin float64 `json:"temp"`
-		} `json:"main"`
+			obs = append(obs, res.obs)
+			if len(obs) >= quorum {
+				break collect
+			}
+		case <-ctx.Done():
+			break collect
+		}
 	}
 
-	// use json.NewDecoder to unmarshall the API response into a wweatherData object
-	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
-		return 0, err
+	if len(obs) < quorum {
+		return providers.Observation{}, fmt.Errorf("weather: only %d/%d providers replied (need %d): %w", len(obs), len(w.providers), quorum, errors.Join(errs...))
 	}
 
-	log.Printf("openWeatherMap: %s %.2f", city, d.Main.Kelvin)
-	// return the weatherData to the caller, with a nil error to indicate success.
-	return d.Main.Kelvin, nil
+	return mergeObservations(obs), nil
 }
 
-type weatherUnderground struct {
-	apiKey string
-}
+// mergeObservations combines one Observation per provider into a single
+// reading: scalars are averaged, Conditions is the union of every
+// provider's descriptions, and Summary is whichever category got the
+// most votes.
+//
+// Temp, WindSpeed, WindDeg and Coord are reported by every provider in
+// this package, so they're summed unconditionally and divided by n.
+// FeelsLike, Humidity, Pressure, CloudCover and Visibility are left
+// zero-valued by providers that don't report them (Open-Meteo, for
+// one) -- treating that zero as a real reading would silently pull the
+// average toward nonsense, so like Sunrise/Sunset below, each is only
+// summed where reported and divided by its own count.
+func mergeObservations(obs []providers.Observation) providers.Observation {
+	n := float64(len(obs))
 
-func (w weatherUnderground) temperature(city string) (float64, error) {
-	resp, err := http.Get("http://api.wunderground.com/api/" + w.apiKey + "/conditions/q/" + city + ".json")
-	if err != nil {
-		return 0, err
+	var sum struct {
+		temp, windSpeed, windDeg float64
+		lat, lon                 float64
 	}
 
-	defer resp.Body.Close()
+	var feelsLike, humidity, pressure, cloudCover, visibility float64
+	var feelsLikeN, humidityN, pressureN, cloudCoverN, visibilityN int
 
-	var d struct {
-		Observation struct {
-			Celsius float64 `json:"temp_c"`
-		} `json:current_observation"`
-	}
+	seen := map[string]bool{}
+	var conditions []string
+	votes := map[string]int{}
 
-	// use json.NewDecoder to unmarshall the API response into a wweatherData object
-	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
-		return 0, err
-	}
+	var sunrise, sunset int64
+	var sunriseN, sunsetN int
 
-	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
-		return 0, err
-	}
+	for _, o := range obs {
+		sum.temp += o.Temp.Kelvin()
+		sum.windSpeed += o.WindSpeed.MetersPerSecond()
+		sum.windDeg += o.WindDeg
+		sum.lat += o.Coord.Lat
+		sum.lon += o.Coord.Lon
 
-	kelvin := d.Observation.Celsius + 273.15
-	log.Printf("weatherUnderground: %s %.2f", city, kelvin)
-	return kelvin, nil
-}
+		if o.FeelsLike.Kelvin() != 0 {
+			feelsLike += o.FeelsLike.Kelvin()
+			feelsLikeN++
+		}
+		if o.Humidity != 0 {
+			humidity += o.Humidity
+			humidityN++
+		}
+		if o.Pressure.HPa() != 0 {
+			pressure += o.Pressure.HPa()
+			pressureN++
+		}
+		if o.CloudCover != 0 {
+			cloudCover += o.CloudCover
+			cloudCoverN++
+		}
+		if o.Visibility != 0 {
+			visibility += o.Visibility
+			visibilityN++
+		}
 
-func temperature(city string, providers ...weatherProvider) (float64, error) {
-	sum := 0.0
+		for _, c := range o.Conditions {
+			if !seen[c] {
+				seen[c] = true
+				conditions = append(conditions, c)
+			}
+		}
 
-	for _, provider := range providers {
-		k, err := provider.temperature(city)
-		if err != nil {
-			return 0, err
+		if o.Summary != "" {
+			votes[o.Summary]++
 		}
 
-		sum += k
+		if !o.Sunrise.IsZero() {
+			sunrise += o.Sunrise.Unix()
+			sunriseN++
+		}
+		if !o.Sunset.IsZero() {
+			sunset += o.Sunset.Unix()
+			sunsetN++
+		}
 	}
 
-	return sum / float64(len(providers)), nil
-}
+	merged := providers.Observation{
+		Temp:       units.NewTemperature(sum.temp/n, units.Kelvin),
+		WindSpeed:  units.NewSpeed(sum.windSpeed/n, units.Kelvin),
+		WindDeg:    sum.windDeg / n,
+		Conditions: conditions,
+		Summary:    majority(votes),
+		Coord:      providers.Coord{Lat: sum.lat / n, Lon: sum.lon / n},
+	}
+
+	if feelsLikeN > 0 {
+		merged.FeelsLike = units.NewTemperature(feelsLike/float64(feelsLikeN), units.Kelvin)
+	}
+	if humidityN > 0 {
+		merged.Humidity = humidity / float64(humidityN)
+	}
+	if pressureN > 0 {
+		merged.Pressure = units.NewPressure(pressure/float64(pressureN), units.Kelvin)
+	}
+	if cloudCoverN > 0 {
+		merged.CloudCover = cloudCover / float64(cloudCoverN)
+	}
+	if visibilityN > 0 {
+		merged.Visibility = visibility / float64(visibilityN)
+	}
 
-type multiWeatherProvider []weatherProvider
+	if sunriseN > 0 {
+		merged.Sunrise = time.Unix(sunrise/int64(sunriseN), 0)
+	}
+	if sunsetN > 0 {
+		merged.Sunset = time.Unix(sunset/int64(sunsetN), 0)
+	}
 
-func (w multiWeatherProvider) temperature(city string) (float64, error) {
-	sum := 0.0
+	return merged
+}
 
-	for _, provider := range w {
-		k, err := provider.temperature(city)
-		if err != nil {
-			return 0, err
+// majority returns the key with the highest vote count, or "" if votes
+// is empty. Ties break arbitrarily.
+func majority(votes map[string]int) string {
+	var best string
+	var bestN int
+	for k, v := range votes {
+		if v > bestN {
+			best, bestN = k, v
 		}
-
-		sum += k
 	}
-
-	return sum / float64(len(w)), nil
+	return best
 }
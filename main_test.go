@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grggls/go-hello/geocode"
+	"github.com/grggls/go-hello/providers"
+	"github.com/grggls/go-hello/units"
+)
+
+// fakeProvider is a providers.Provider stub that returns a canned
+// Observation (or error) after an optional delay, honoring ctx like a
+// real network call would.
+type fakeProvider struct {
+	obs   providers.Observation
+	err   error
+	delay time.Duration
+}
+
+func (f fakeProvider) Observe(ctx context.Context, req providers.Request) (providers.Observation, error) {
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return providers.Observation{}, ctx.Err()
+		}
+	}
+
+	return f.obs, f.err
+}
+
+func tempObs(kelvin float64, summary string, conditions ...string) providers.Observation {
+	return providers.Observation{
+		Temp:       units.NewTemperature(kelvin, units.Kelvin),
+		Conditions: conditions,
+		Summary:    summary,
+	}
+}
+
+func TestMultiWeatherProviderObserveQuorumMet(t *testing.T) {
+	mw := multiWeatherProvider{
+		providers: []providers.Provider{
+			fakeProvider{obs: tempObs(280, "Clear", "clear sky")},
+			fakeProvider{obs: tempObs(290, "Clear", "clear sky")},
+			fakeProvider{obs: tempObs(300, "Clouds", "overcast")},
+		},
+		// quorum equals the provider count so Observe must wait for all
+		// three replies -- anything lower races ahead on whichever two
+		// goroutines happen to finish first and makes the merge
+		// nondeterministic.
+		quorum: 3,
+	}
+
+	obs, err := mw.Observe(context.Background(), geocode.Location{Name: "Testville"}, 0)
+	if err != nil {
+		t.Fatalf("Observe: %v", err)
+	}
+
+	// mergeObservations is exercised properly here: all three providers
+	// reply well within quorum, so nothing is left out of the average.
+	if want := (280.0 + 290.0 + 300.0) / 3; obs.Temp.Kelvin() != want {
+		t.Errorf("Temp.Kelvin() = %v, want %v", obs.Temp.Kelvin(), want)
+	}
+}
+
+func TestMultiWeatherProviderObserveQuorumNotMet(t *testing.T) {
+	boom := errors.New("boom")
+	mw := multiWeatherProvider{
+		providers: []providers.Provider{
+			fakeProvider{obs: tempObs(280, "Clear")},
+			fakeProvider{err: boom},
+			fakeProvider{err: boom},
+		},
+		quorum: 2,
+	}
+
+	_, err := mw.Observe(context.Background(), geocode.Location{Name: "Testville"}, 0)
+	if err == nil {
+		t.Fatal("expected an error when fewer than quorum providers succeed, got nil")
+	}
+}
+
+func TestMultiWeatherProviderObserveContextExpiresBeforeQuorum(t *testing.T) {
+	mw := multiWeatherProvider{
+		providers: []providers.Provider{
+			fakeProvider{obs: tempObs(280, "Clear"), delay: 5 * time.Millisecond},
+			fakeProvider{obs: tempObs(290, "Clear"), delay: time.Hour},
+			fakeProvider{obs: tempObs(300, "Clear"), delay: time.Hour},
+		},
+		quorum: 2,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := mw.Observe(ctx, geocode.Location{Name: "Testville"}, 0)
+	if err == nil {
+		t.Fatal("expected an error when ctx expires with only a partial reply, got nil")
+	}
+}
+
+func TestMultiWeatherProviderObserveQuorumOverride(t *testing.T) {
+	mw := multiWeatherProvider{
+		providers: []providers.Provider{
+			fakeProvider{obs: tempObs(280, "Clear"), delay: 5 * time.Millisecond},
+			fakeProvider{obs: tempObs(290, "Clear"), delay: time.Hour},
+			fakeProvider{obs: tempObs(300, "Clear"), delay: time.Hour},
+		},
+		quorum: 2,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// a per-call override of 1 should succeed on the single fast reply
+	// even though mw.quorum asks for 2.
+	obs, err := mw.Observe(ctx, geocode.Location{Name: "Testville"}, 1)
+	if err != nil {
+		t.Fatalf("Observe: %v", err)
+	}
+	if obs.Temp.Kelvin() != 280 {
+		t.Errorf("Temp.Kelvin() = %v, want 280", obs.Temp.Kelvin())
+	}
+}
+
+func TestMergeObservations(t *testing.T) {
+	obs := []providers.Observation{
+		tempObs(280, "Clear", "clear sky"),
+		tempObs(290, "Clear", "few clouds"),
+		tempObs(300, "Clouds", "overcast"),
+	}
+
+	merged := mergeObservations(obs)
+
+	if want := (280.0 + 290.0 + 300.0) / 3; merged.Temp.Kelvin() != want {
+		t.Errorf("Temp.Kelvin() = %v, want %v", merged.Temp.Kelvin(), want)
+	}
+
+	if merged.Summary != "Clear" {
+		t.Errorf("Summary = %q, want %q (two of three providers voted Clear)", merged.Summary, "Clear")
+	}
+
+	wantConditions := map[string]bool{"clear sky": true, "few clouds": true, "overcast": true}
+	if len(merged.Conditions) != len(wantConditions) {
+		t.Fatalf("Conditions = %v, want the union of %v", merged.Conditions, wantConditions)
+	}
+	for _, c := range merged.Conditions {
+		if !wantConditions[c] {
+			t.Errorf("unexpected condition %q in merged Conditions", c)
+		}
+	}
+}
+
+func TestMergeObservationsSkipsUnreportedFields(t *testing.T) {
+	// Shaped like a real OWM reading.
+	full := providers.Observation{
+		Temp:      units.NewTemperature(280, units.Kelvin),
+		FeelsLike: units.NewTemperature(278, units.Kelvin),
+		Humidity:  60,
+		Pressure:  units.NewPressure(1013, units.Kelvin),
+	}
+	// Shaped like Open-Meteo's reading, which never sets these fields.
+	partial := providers.Observation{
+		Temp: units.NewTemperature(290, units.Kelvin),
+	}
+
+	merged := mergeObservations([]providers.Observation{full, partial})
+
+	if merged.FeelsLike.Kelvin() != 278 {
+		t.Errorf("FeelsLike.Kelvin() = %v, want 278 (only one provider reported it)", merged.FeelsLike.Kelvin())
+	}
+	if merged.Humidity != 60 {
+		t.Errorf("Humidity = %v, want 60 (only one provider reported it)", merged.Humidity)
+	}
+	if merged.Pressure.HPa() != 1013 {
+		t.Errorf("Pressure.HPa() = %v, want 1013 (only one provider reported it)", merged.Pressure.HPa())
+	}
+
+	// Temp is reported by both, so it's a real average.
+	if want := (280.0 + 290.0) / 2; merged.Temp.Kelvin() != want {
+		t.Errorf("Temp.Kelvin() = %v, want %v", merged.Temp.Kelvin(), want)
+	}
+}
+
+func TestMajority(t *testing.T) {
+	if got := majority(map[string]int{"Clear": 2, "Clouds": 1}); got != "Clear" {
+		t.Errorf("majority = %q, want %q", got, "Clear")
+	}
+
+	if got := majority(map[string]int{}); got != "" {
+		t.Errorf("majority(empty) = %q, want %q", got, "")
+	}
+}
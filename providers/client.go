@@ -0,0 +1,26 @@
+package providers
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// httpClient returns client if set, falling back to http.DefaultClient.
+// Providers keep a *http.Client field so tests can inject a stub
+// RoundTripper instead of hitting the network.
+func httpClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return http.DefaultClient
+}
+
+// checkStatus turns a non-200 response into an error identifying which
+// provider and status code produced it, covering 4xx/5xx and rate-limit
+// responses alike.
+func checkStatus(provider string, resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	return fmt.Errorf("%s: unexpected status %s", provider, resp.Status)
+}
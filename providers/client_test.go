@@ -0,0 +1,29 @@
+package providers
+
+import (
+	"math"
+	"net/http"
+)
+
+// closeEnough reports whether a and b are within floating-point rounding
+// error of each other.
+func closeEnough(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// roundTripFunc adapts a function to http.RoundTripper so tests can stub
+// responses without touching the network. It honors context
+// cancellation the way a real transport would, so timeout tests behave
+// realistically.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	return f(req)
+}
+
+func stubClient(fn roundTripFunc) *http.Client {
+	return &http.Client{Transport: fn}
+}
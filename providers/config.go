@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderConfig is the per-provider configuration a Factory receives.
+type ProviderConfig struct {
+	APIKey string `yaml:"api_key"`
+}
+
+// Config selects which providers to build and how to configure them. It
+// is loaded from a YAML file and then overridden by environment
+// variables, so the binary never needs to ship literal API keys:
+//
+//	enabled:
+//	  - owm
+//	  - darksky
+//	providers:
+//	  owm:
+//	    api_key: ...
+//
+// is equivalent to setting WEATHER_PROVIDERS=owm,darksky and
+// OWM_API_KEY=....
+//
+// Quorum is the minimum number of enabled providers that must reply
+// before a request succeeds. Zero means "require all of them" -- see
+// Build, which resolves that default once the enabled set is known.
+type Config struct {
+	Enabled   []string                  `yaml:"enabled"`
+	Providers map[string]ProviderConfig `yaml:"providers"`
+	Quorum    int                       `yaml:"quorum"`
+}
+
+// LoadConfig reads path as YAML (if path is non-empty and exists) and
+// then applies WEATHER_PROVIDERS, WEATHER_QUORUM, and <NAME>_API_KEY
+// environment overrides on top of it.
+func LoadConfig(path string) (Config, error) {
+	cfg := Config{}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return Config{}, err
+			}
+		case os.IsNotExist(err):
+			// no config file is fine; env vars can do the whole job
+		default:
+			return Config{}, err
+		}
+	}
+
+	if raw := os.Getenv("WEATHER_PROVIDERS"); raw != "" {
+		cfg.Enabled = strings.Split(raw, ",")
+	}
+
+	if raw := os.Getenv("WEATHER_QUORUM"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			cfg.Quorum = n
+		}
+	}
+
+	if cfg.Providers == nil {
+		cfg.Providers = map[string]ProviderConfig{}
+	}
+
+	for _, name := range cfg.Enabled {
+		key := strings.ToUpper(name) + "_API_KEY"
+		if v := os.Getenv(key); v != "" {
+			pc := cfg.Providers[name]
+			pc.APIKey = v
+			cfg.Providers[name] = pc
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,123 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/go-querystring/query"
+
+	"github.com/grggls/go-hello/units"
+)
+
+func init() {
+	Register("darksky", func(cfg ProviderConfig) (Provider, error) {
+		return darkSky{apiKey: cfg.APIKey}, nil
+	})
+}
+
+type darkSky struct {
+	apiKey string
+	client *http.Client
+}
+
+// darkSkyOptions are the query-string options DarkSky's forecast
+// endpoint accepts, encoded with go-querystring.
+type darkSkyOptions struct {
+	Exclude string `url:"exclude,omitempty"`
+	Units   string `url:"units,omitempty"`
+}
+
+// Observe fetches a reading from DarkSky for req, which must carry
+// resolved coordinates -- DarkSky has no free-form city search.
+func (d darkSky) Observe(ctx context.Context, req Request) (Observation, error) {
+	if !req.HasCoord() {
+		return Observation{}, fmt.Errorf("darksky: requires coordinates, got city %q", req.City)
+	}
+
+	values, err := query.Values(darkSkyOptions{Exclude: "minutely,hourly,daily,alerts", Units: "si"})
+	if err != nil {
+		return Observation{}, err
+	}
+
+	url := fmt.Sprintf("https://api.darksky.net/forecast/%s/%f,%f?%s", d.apiKey, *req.Lat, *req.Lon, values.Encode())
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	resp, err := httpClient(d.client).Do(httpReq)
+	if err != nil {
+		return Observation{}, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus("darksky", resp); err != nil {
+		return Observation{}, err
+	}
+
+	obs, err := ParseDarkSkyResponse(resp.Body)
+	if err != nil {
+		return Observation{}, err
+	}
+	obs.Coord = Coord{Lat: *req.Lat, Lon: *req.Lon}
+
+	log.Printf("darksky: %f,%f %.2f", *req.Lat, *req.Lon, obs.Temp.Kelvin())
+	return obs, nil
+}
+
+// ParseDarkSkyResponse decodes a DarkSky "forecast" JSON response
+// (`units=si`) into an Observation. Coord is left zero-valued since the
+// response doesn't echo the request's coordinates back.
+func ParseDarkSkyResponse(r io.Reader) (Observation, error) {
+	var body struct {
+		Currently struct {
+			Temperature  float64 `json:"temperature"` // celsius, "si" units
+			ApparentTemp float64 `json:"apparentTemperature"`
+			Humidity     float64 `json:"humidity"` // 0-1
+			Pressure     float64 `json:"pressure"`
+			WindSpeed    float64 `json:"windSpeed"`
+			WindBearing  float64 `json:"windBearing"`
+			CloudCover   float64 `json:"cloudCover"` // 0-1
+			Visibility   float64 `json:"visibility"` // km
+			Summary      string  `json:"summary"`
+			SunriseTime  int64   `json:"sunriseTime"`
+			SunsetTime   int64   `json:"sunsetTime"`
+		} `json:"currently"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return Observation{}, err
+	}
+
+	var conditions []string
+	if body.Currently.Summary != "" {
+		conditions = []string{body.Currently.Summary}
+	}
+
+	obs := Observation{
+		Temp:       units.NewTemperature(body.Currently.Temperature+273.15, units.Kelvin),
+		FeelsLike:  units.NewTemperature(body.Currently.ApparentTemp+273.15, units.Kelvin),
+		Humidity:   body.Currently.Humidity * 100,
+		Pressure:   units.NewPressure(body.Currently.Pressure, units.Kelvin),
+		WindSpeed:  units.NewSpeed(body.Currently.WindSpeed, units.Kelvin),
+		WindDeg:    body.Currently.WindBearing,
+		CloudCover: body.Currently.CloudCover * 100,
+		Visibility: body.Currently.Visibility * 1000,
+		Conditions: conditions,
+		Summary:    body.Currently.Summary,
+	}
+	if body.Currently.SunriseTime > 0 {
+		obs.Sunrise = time.Unix(body.Currently.SunriseTime, 0)
+	}
+	if body.Currently.SunsetTime > 0 {
+		obs.Sunset = time.Unix(body.Currently.SunsetTime, 0)
+	}
+
+	return obs, nil
+}
@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseDarkSkyResponse(t *testing.T) {
+	data, err := os.ReadFile("testdata/darksky.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ParseDarkSkyResponse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseDarkSkyResponse: %v", err)
+	}
+
+	if got, want := obs.Temp.Kelvin(), 12.2+273.15; !closeEnough(got, want) {
+		t.Errorf("Temp.Kelvin() = %v, want %v", got, want)
+	}
+	if got, want := obs.Summary, "Partly Cloudy"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+}
+
+func TestParseDarkSkyResponseMalformed(t *testing.T) {
+	if _, err := ParseDarkSkyResponse(strings.NewReader("{not valid json")); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func TestDarkSkyObserveRequiresCoord(t *testing.T) {
+	d := darkSky{apiKey: "test"}
+	if _, err := d.Observe(context.Background(), Request{City: "London"}); err == nil {
+		t.Fatal("expected an error for a request with no coordinates, got nil")
+	}
+}
+
+func TestDarkSkyObserve(t *testing.T) {
+	lat, lon := 51.5085, -0.1257
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{name: "ok", status: http.StatusOK, body: mustReadFile(t, "testdata/darksky.json")},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: `{"error":"too many requests"}`, wantErr: true},
+		{name: "server error", status: http.StatusServiceUnavailable, body: "unavailable", wantErr: true},
+		{name: "malformed json", status: http.StatusOK, body: "{not valid json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := darkSky{
+				apiKey: "test",
+				client: stubClient(func(r *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: tt.status,
+						Body:       io.NopCloser(strings.NewReader(tt.body)),
+						Header:     make(http.Header),
+					}, nil
+				}),
+			}
+
+			_, err := d.Observe(context.Background(), Request{Lat: &lat, Lon: &lon})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"time"
+
+	"github.com/grggls/go-hello/units"
+)
+
+// Coord is the latitude/longitude a provider reported an Observation for.
+type Coord struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+/*
+ * Observation is the rich weather reading providers return, replacing the
+ * single squeezed-through Kelvin float64 this package started with.
+ * Temp, FeelsLike, Pressure and WindSpeed carry their own units and know
+ * how to render themselves in whatever System the caller asked for; see
+ * the units package.
+ */
+type Observation struct {
+	Temp       units.Temperature `json:"temp"`
+	FeelsLike  units.Temperature `json:"feels_like"`
+	Humidity   float64           `json:"humidity"` // percent
+	Pressure   units.Pressure    `json:"pressure"`
+	WindSpeed  units.Speed       `json:"wind_speed"`
+	WindDeg    float64           `json:"wind_deg"`    // degrees
+	CloudCover float64           `json:"cloud_cover"` // percent
+	Visibility float64           `json:"visibility"`  // meters
+	Conditions []string          `json:"conditions"`  // union of reported descriptions, e.g. "light rain"
+	Summary    string            `json:"summary"`     // majority-voted category, e.g. "Rain"
+	Sunrise    time.Time         `json:"sunrise"`
+	Sunset     time.Time         `json:"sunset"`
+	Coord      Coord             `json:"coord"`
+}
+
+// In returns a copy of o with its unit-aware fields rendered in system,
+// leaving the canonical values they carry untouched.
+func (o Observation) In(system units.System) Observation {
+	o.Temp = units.NewTemperature(o.Temp.Kelvin(), system)
+	o.FeelsLike = units.NewTemperature(o.FeelsLike.Kelvin(), system)
+	o.Pressure = units.NewPressure(o.Pressure.HPa(), system)
+	o.WindSpeed = units.NewSpeed(o.WindSpeed.MetersPerSecond(), system)
+	return o
+}
@@ -0,0 +1,103 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/grggls/go-hello/units"
+)
+
+func init() {
+	Register("openmeteo", func(cfg ProviderConfig) (Provider, error) {
+		return openMeteo{}, nil
+	})
+}
+
+// openMeteo talks to the free Open-Meteo API, which needs no key.
+type openMeteo struct {
+	client *http.Client
+}
+
+// weatherCodeSummary maps Open-Meteo's WMO weather codes to the short
+// categories the other providers report as Observation.Summary.
+// https://open-meteo.com/en/docs#weathervariables
+var weatherCodeSummary = map[int]string{
+	0: "Clear", 1: "Clear", 2: "Clouds", 3: "Clouds",
+	45: "Fog", 48: "Fog",
+	51: "Drizzle", 53: "Drizzle", 55: "Drizzle",
+	61: "Rain", 63: "Rain", 65: "Rain",
+	71: "Snow", 73: "Snow", 75: "Snow",
+	80: "Rain", 81: "Rain", 82: "Rain",
+	95: "Thunderstorm", 96: "Thunderstorm", 99: "Thunderstorm",
+}
+
+// Observe fetches a reading from Open-Meteo for req, which must carry
+// resolved coordinates -- Open-Meteo has no free-form city search.
+func (o openMeteo) Observe(ctx context.Context, req Request) (Observation, error) {
+	if !req.HasCoord() {
+		return Observation{}, fmt.Errorf("openmeteo: requires coordinates, got city %q", req.City)
+	}
+
+	url := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&current_weather=true", *req.Lat, *req.Lon)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	resp, err := httpClient(o.client).Do(httpReq)
+	if err != nil {
+		return Observation{}, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus("openmeteo", resp); err != nil {
+		return Observation{}, err
+	}
+
+	obs, err := ParseOpenMeteoResponse(resp.Body)
+	if err != nil {
+		return Observation{}, err
+	}
+	obs.Coord = Coord{Lat: *req.Lat, Lon: *req.Lon}
+
+	log.Printf("openmeteo: %f,%f %.2f", *req.Lat, *req.Lon, obs.Temp.Kelvin())
+	return obs, nil
+}
+
+// ParseOpenMeteoResponse decodes an Open-Meteo "forecast" JSON response
+// into an Observation. Coord is left zero-valued since the response
+// doesn't echo the request's coordinates back in a convenient form.
+func ParseOpenMeteoResponse(r io.Reader) (Observation, error) {
+	var body struct {
+		CurrentWeather struct {
+			Temperature   float64 `json:"temperature"` // celsius
+			WindSpeed     float64 `json:"windspeed"`   // km/h
+			WindDirection float64 `json:"winddirection"`
+			WeatherCode   int     `json:"weathercode"`
+		} `json:"current_weather"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return Observation{}, err
+	}
+
+	summary := weatherCodeSummary[body.CurrentWeather.WeatherCode]
+
+	var conditions []string
+	if summary != "" {
+		conditions = []string{summary}
+	}
+
+	return Observation{
+		Temp:       units.NewTemperature(body.CurrentWeather.Temperature+273.15, units.Kelvin),
+		WindSpeed:  units.NewSpeed(body.CurrentWeather.WindSpeed/3.6, units.Kelvin),
+		WindDeg:    body.CurrentWeather.WindDirection,
+		Conditions: conditions,
+		Summary:    summary,
+	}, nil
+}
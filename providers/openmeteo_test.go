@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseOpenMeteoResponse(t *testing.T) {
+	data, err := os.ReadFile("testdata/openmeteo.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ParseOpenMeteoResponse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseOpenMeteoResponse: %v", err)
+	}
+
+	if got, want := obs.Temp.Kelvin(), 12.2+273.15; !closeEnough(got, want) {
+		t.Errorf("Temp.Kelvin() = %v, want %v", got, want)
+	}
+	if got, want := obs.Summary, "Clouds"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+}
+
+func TestParseOpenMeteoResponseMalformed(t *testing.T) {
+	if _, err := ParseOpenMeteoResponse(strings.NewReader("{not valid json")); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func TestOpenMeteoObserveRequiresCoord(t *testing.T) {
+	o := openMeteo{}
+	if _, err := o.Observe(context.Background(), Request{City: "London"}); err == nil {
+		t.Fatal("expected an error for a request with no coordinates, got nil")
+	}
+}
+
+func TestOpenMeteoObserve(t *testing.T) {
+	lat, lon := 51.5, -0.12
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{name: "ok", status: http.StatusOK, body: mustReadFile(t, "testdata/openmeteo.json")},
+		{name: "server error", status: http.StatusInternalServerError, body: "internal error", wantErr: true},
+		{name: "malformed json", status: http.StatusOK, body: "{not valid json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := openMeteo{
+				client: stubClient(func(r *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: tt.status,
+						Body:       io.NopCloser(strings.NewReader(tt.body)),
+						Header:     make(http.Header),
+					}, nil
+				}),
+			}
+
+			_, err := o.Observe(context.Background(), Request{Lat: &lat, Lon: &lon})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/grggls/go-hello/units"
+)
+
+func init() {
+	Register("owm", func(cfg ProviderConfig) (Provider, error) {
+		return openWeatherMap{apiKey: cfg.APIKey}, nil
+	})
+}
+
+type openWeatherMap struct {
+	apiKey string
+	client *http.Client
+}
+
+// Observe fetches a reading from OpenWeatherMap for req, preferring
+// coordinates over the free-form city name when both are available.
+func (w openWeatherMap) Observe(ctx context.Context, req Request) (Observation, error) {
+	url := "http://api.openweathermap.org/data/2.5/weather?APPID=" + w.apiKey
+	if req.HasCoord() {
+		url += fmt.Sprintf("&lat=%f&lon=%f", *req.Lat, *req.Lon)
+	} else {
+		url += "&q=" + req.City
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	resp, err := httpClient(w.client).Do(httpReq)
+	if err != nil {
+		return Observation{}, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus("owm", resp); err != nil {
+		return Observation{}, err
+	}
+
+	obs, err := ParseOWMResponse(resp.Body)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	log.Printf("owm: %s %.2f", req.City, obs.Temp.Kelvin())
+	return obs, nil
+}
+
+// ParseOWMResponse decodes an OpenWeatherMap "current weather" JSON
+// response into an Observation.
+func ParseOWMResponse(r io.Reader) (Observation, error) {
+	var d struct {
+		Coord struct {
+			Lat float64 `json:"lat"`
+			Lon float64 `json:"lon"`
+		} `json:"coord"`
+		Weather []struct {
+			Main        string `json:"main"`
+			Description string `json:"description"`
+		} `json:"weather"`
+		Main struct {
+			Kelvin    float64 `json:"temp"`
+			FeelsLike float64 `json:"feels_like"`
+			Pressure  float64 `json:"pressure"`
+			Humidity  float64 `json:"humidity"`
+		} `json:"main"`
+		Visibility float64 `json:"visibility"`
+		Wind       struct {
+			Speed float64 `json:"speed"`
+			Deg   float64 `json:"deg"`
+		} `json:"wind"`
+		Clouds struct {
+			All float64 `json:"all"`
+		} `json:"clouds"`
+		Sys struct {
+			Sunrise int64 `json:"sunrise"`
+			Sunset  int64 `json:"sunset"`
+		} `json:"sys"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return Observation{}, err
+	}
+
+	var conditions []string
+	var summary string
+	for _, cond := range d.Weather {
+		conditions = append(conditions, cond.Description)
+		if summary == "" {
+			summary = cond.Main
+		}
+	}
+
+	return Observation{
+		Temp:       units.NewTemperature(d.Main.Kelvin, units.Kelvin),
+		FeelsLike:  units.NewTemperature(d.Main.FeelsLike, units.Kelvin),
+		Humidity:   d.Main.Humidity,
+		Pressure:   units.NewPressure(d.Main.Pressure, units.Kelvin),
+		WindSpeed:  units.NewSpeed(d.Wind.Speed, units.Kelvin),
+		WindDeg:    d.Wind.Deg,
+		CloudCover: d.Clouds.All,
+		Visibility: d.Visibility,
+		Conditions: conditions,
+		Summary:    summary,
+		Sunrise:    time.Unix(d.Sys.Sunrise, 0),
+		Sunset:     time.Unix(d.Sys.Sunset, 0),
+		Coord:      Coord{Lat: d.Coord.Lat, Lon: d.Coord.Lon},
+	}, nil
+}
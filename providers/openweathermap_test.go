@@ -0,0 +1,103 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseOWMResponse(t *testing.T) {
+	data, err := os.ReadFile("testdata/owm.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ParseOWMResponse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseOWMResponse: %v", err)
+	}
+
+	if got, want := obs.Temp.Kelvin(), 285.32; got != want {
+		t.Errorf("Temp.Kelvin() = %v, want %v", got, want)
+	}
+	if got, want := obs.Summary, "Clouds"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+	if got, want := obs.Conditions, []string{"broken clouds"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Conditions = %v, want %v", got, want)
+	}
+	if got, want := obs.Coord, (Coord{Lat: 51.5085, Lon: -0.1257}); got != want {
+		t.Errorf("Coord = %v, want %v", got, want)
+	}
+}
+
+func TestParseOWMResponseMalformed(t *testing.T) {
+	if _, err := ParseOWMResponse(strings.NewReader("{not valid json")); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func TestOpenWeatherMapObserve(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr bool
+	}{
+		{name: "ok", status: http.StatusOK, body: mustReadFile(t, "testdata/owm.json")},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: mustReadFile(t, "testdata/rate_limited.json"), wantErr: true},
+		{name: "server error", status: http.StatusInternalServerError, body: "internal error", wantErr: true},
+		{name: "malformed json", status: http.StatusOK, body: "{not valid json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := openWeatherMap{
+				apiKey: "test",
+				client: stubClient(func(r *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: tt.status,
+						Body:       io.NopCloser(strings.NewReader(tt.body)),
+						Header:     make(http.Header),
+					}, nil
+				}),
+			}
+
+			_, err := w.Observe(context.Background(), Request{City: "London"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenWeatherMapObserveTimeout(t *testing.T) {
+	w := openWeatherMap{
+		apiKey: "test",
+		client: stubClient(func(r *http.Request) (*http.Response, error) {
+			t.Fatal("transport should not be reached once the context is done")
+			return nil, nil
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := w.Observe(ctx, Request{City: "London"}); err == nil {
+		t.Fatal("expected an error from a canceled context, got nil")
+	}
+}
+
+func mustReadFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,82 @@
+// Package providers is the pluggable registry of weather upstreams. Each
+// provider registers a Factory under a name (e.g. "owm"); main builds the
+// set it wants from a Config rather than constructing providers by hand,
+// so adding an upstream never touches main.go.
+package providers
+
+import (
+	"context"
+	"fmt"
+)
+
+// Request is what a Provider is asked to observe. City is a free-form
+// place name; Lat/Lon are set once a geocoder resolves it, which
+// providers that require coordinates (DarkSky, Open-Meteo) depend on.
+//
+// Request itself isn't encoded with go-querystring -- every provider's
+// URL shape is different enough (query param names, path segments,
+// whether coordinates are even accepted) that each builds its own URL
+// by hand. Providers with their own option structs (e.g. DarkSky's
+// darkSkyOptions) use go-querystring for those instead.
+type Request struct {
+	City string
+	Lat  *float64
+	Lon  *float64
+}
+
+// HasCoord reports whether r carries a resolved lat/lon.
+func (r Request) HasCoord() bool {
+	return r.Lat != nil && r.Lon != nil
+}
+
+// Provider fetches a single Observation for a Request.
+type Provider interface {
+	Observe(ctx context.Context, req Request) (Observation, error)
+}
+
+// Factory builds a Provider from its ProviderConfig. Factories are
+// registered by name via Register and looked up by Build.
+type Factory func(cfg ProviderConfig) (Provider, error)
+
+var registry = map[string]Factory{}
+
+// Register makes a Factory available under name. It's meant to be called
+// from a provider's init() function, mirroring how database/sql drivers
+// register themselves.
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+// New builds the named provider from cfg. It returns an error if no
+// Factory was registered under that name.
+func New(name string, cfg ProviderConfig) (Provider, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("providers: no provider registered for %q", name)
+	}
+
+	return factory(cfg)
+}
+
+// Named pairs a built Provider with the registry name it was built
+// under, for callers (like cache.New) that need it for labeling.
+type Named struct {
+	Name     string
+	Provider Provider
+}
+
+// Build constructs every provider cfg.Enabled names, in order.
+func Build(cfg Config) ([]Named, error) {
+	built := make([]Named, 0, len(cfg.Enabled))
+
+	for _, name := range cfg.Enabled {
+		p, err := New(name, cfg.Providers[name])
+		if err != nil {
+			return nil, err
+		}
+
+		built = append(built, Named{Name: name, Provider: p})
+	}
+
+	return built, nil
+}
@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/grggls/go-hello/units"
+)
+
+func init() {
+	Register("wunderground", func(cfg ProviderConfig) (Provider, error) {
+		return weatherUnderground{apiKey: cfg.APIKey}, nil
+	})
+}
+
+type weatherUnderground struct {
+	apiKey string
+	client *http.Client
+}
+
+// Observe fetches a reading from Weather Underground for req. The
+// conditions endpoint reports several numeric fields as quoted strings
+// and has no sunrise/sunset data (that's a separate astronomy call), so
+// those fields are parsed leniently and left zero-valued respectively.
+func (w weatherUnderground) Observe(ctx context.Context, req Request) (Observation, error) {
+	query := req.City
+	if req.HasCoord() {
+		query = fmt.Sprintf("%f,%f", *req.Lat, *req.Lon)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://api.wunderground.com/api/"+w.apiKey+"/conditions/q/"+query+".json", nil)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	resp, err := httpClient(w.client).Do(httpReq)
+	if err != nil {
+		return Observation{}, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus("wunderground", resp); err != nil {
+		return Observation{}, err
+	}
+
+	obs, err := ParseWundergroundResponse(resp.Body)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	log.Printf("wunderground: %s %.2f", req.City, obs.Temp.Kelvin())
+	return obs, nil
+}
+
+// ParseWundergroundResponse decodes a Weather Underground "conditions"
+// JSON response into an Observation.
+func ParseWundergroundResponse(r io.Reader) (Observation, error) {
+	var d struct {
+		Observation struct {
+			Celsius         float64 `json:"temp_c"`
+			FeelsLikeString string  `json:"feelslike_c"`
+			RelativeHumid   string  `json:"relative_humidity"`
+			PressureMb      string  `json:"pressure_mb"`
+			WindKph         float64 `json:"wind_kph"`
+			WindDegrees     float64 `json:"wind_degrees"`
+			Weather         string  `json:"weather"`
+			VisibilityKm    string  `json:"visibility_km"`
+		} `json:"current_observation"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return Observation{}, err
+	}
+
+	feelsLikeC := parseLenientFloat(d.Observation.FeelsLikeString)
+	humidity := parseLenientFloat(strings.TrimSuffix(d.Observation.RelativeHumid, "%"))
+	pressureMb := parseLenientFloat(d.Observation.PressureMb)
+	visibilityKm := parseLenientFloat(d.Observation.VisibilityKm)
+
+	kelvin := d.Observation.Celsius + 273.15
+
+	var conditions []string
+	if d.Observation.Weather != "" {
+		conditions = []string{d.Observation.Weather}
+	}
+
+	return Observation{
+		Temp:       units.NewTemperature(kelvin, units.Kelvin),
+		FeelsLike:  units.NewTemperature(feelsLikeC+273.15, units.Kelvin),
+		Humidity:   humidity,
+		Pressure:   units.NewPressure(pressureMb, units.Kelvin),
+		WindSpeed:  units.NewSpeed(d.Observation.WindKph/3.6, units.Kelvin),
+		WindDeg:    d.Observation.WindDegrees,
+		Visibility: visibilityKm * 1000,
+		Conditions: conditions,
+		Summary:    d.Observation.Weather,
+	}, nil
+}
+
+// parseLenientFloat parses s as a float64, returning 0 if s is empty or
+// not parseable -- Weather Underground's conditions endpoint omits or
+// blanks fields it doesn't have for a given station.
+func parseLenientFloat(s string) float64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+
+	var v float64
+	if _, err := fmt.Sscanf(s, "%f", &v); err != nil {
+		return 0
+	}
+
+	return v
+}
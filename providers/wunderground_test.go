@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseWundergroundResponse(t *testing.T) {
+	data, err := os.ReadFile("testdata/wunderground.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ParseWundergroundResponse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseWundergroundResponse: %v", err)
+	}
+
+	if got, want := obs.Temp.Kelvin(), 12.2+273.15; !closeEnough(got, want) {
+		t.Errorf("Temp.Kelvin() = %v, want %v", got, want)
+	}
+	if got, want := obs.Humidity, 76.0; got != want {
+		t.Errorf("Humidity = %v, want %v", got, want)
+	}
+	if got, want := obs.Summary, "Mostly Cloudy"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+}
+
+func TestParseWundergroundResponseMalformed(t *testing.T) {
+	if _, err := ParseWundergroundResponse(strings.NewReader("{not valid json")); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func TestWeatherUndergroundObserve(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{name: "ok", status: http.StatusOK, body: mustReadFile(t, "testdata/wunderground.json")},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: mustReadFile(t, "testdata/rate_limited.json"), wantErr: true},
+		{name: "server error", status: http.StatusBadGateway, body: "bad gateway", wantErr: true},
+		{name: "malformed json", status: http.StatusOK, body: "{not valid json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := weatherUnderground{
+				apiKey: "test",
+				client: stubClient(func(r *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: tt.status,
+						Body:       io.NopCloser(strings.NewReader(tt.body)),
+						Header:     make(http.Header),
+					}, nil
+				}),
+			}
+
+			_, err := w.Observe(context.Background(), Request{City: "London"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Package units holds typed, unit-aware values for weather observations.
+//
+// Every value keeps its canonical SI measurement internally (kelvin,
+// meters/second, hectopascals) and a System describing which units the
+// caller asked for. MarshalJSON renders the value converted into that
+// System; the canonical value is still available via the Kelvin/
+// MetersPerSecond/HPa accessors for anything that needs to do math
+// across values (e.g. averaging several providers' observations).
+package units
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// System is a unit system a caller can request via `?units=`.
+type System string
+
+const (
+	// Kelvin is the zero-value System: temperatures in kelvin, speeds in
+	// meters/second, pressures in hectopascals. Matches the historical
+	// behavior of this package before unit selection existed.
+	Kelvin System = "kelvin"
+	// Metric reports temperatures in Celsius, speeds in meters/second,
+	// pressures in hectopascals.
+	Metric System = "metric"
+	// Imperial reports temperatures in Fahrenheit, speeds in miles/hour,
+	// pressures in inches of mercury.
+	Imperial System = "imperial"
+)
+
+// ParseSystem maps a `?units=` query value to a System, defaulting to
+// Kelvin for an empty string.
+func ParseSystem(s string) (System, error) {
+	switch System(s) {
+	case "":
+		return Kelvin, nil
+	case Kelvin, Metric, Imperial:
+		return System(s), nil
+	default:
+		return "", fmt.Errorf("units: unknown system %q", s)
+	}
+}
+
+// Temperature is a temperature value that marshals in its System's units.
+type Temperature struct {
+	kelvin float64
+	system System
+}
+
+// NewTemperature builds a Temperature from a canonical kelvin value.
+func NewTemperature(kelvin float64, system System) Temperature {
+	return Temperature{kelvin: kelvin, system: system}
+}
+
+// Kelvin returns the canonical value, regardless of System.
+func (t Temperature) Kelvin() float64 { return t.kelvin }
+
+// Value returns the temperature converted into t's System.
+func (t Temperature) Value() float64 {
+	switch t.system {
+	case Metric:
+		return t.kelvin - 273.15
+	case Imperial:
+		return (t.kelvin-273.15)*9/5 + 32
+	default:
+		return t.kelvin
+	}
+}
+
+func (t Temperature) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Value())
+}
+
+// UnmarshalJSON decodes a raw number in t's System, set beforehand, into
+// the canonical kelvin value.
+func (t *Temperature) UnmarshalJSON(data []byte) error {
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch t.system {
+	case Metric:
+		t.kelvin = v + 273.15
+	case Imperial:
+		t.kelvin = (v - 32) * 5 / 9
+		t.kelvin += 273.15
+	default:
+		t.kelvin = v
+	}
+
+	return nil
+}
+
+// Speed is a wind speed value that marshals in its System's units.
+type Speed struct {
+	metersPerSecond float64
+	system          System
+}
+
+// NewSpeed builds a Speed from a canonical meters/second value.
+func NewSpeed(metersPerSecond float64, system System) Speed {
+	return Speed{metersPerSecond: metersPerSecond, system: system}
+}
+
+// MetersPerSecond returns the canonical value, regardless of System.
+func (s Speed) MetersPerSecond() float64 { return s.metersPerSecond }
+
+// Value returns the speed converted into s's System.
+func (s Speed) Value() float64 {
+	if s.system == Imperial {
+		return s.metersPerSecond * 2.23694 // mph
+	}
+	return s.metersPerSecond
+}
+
+func (s Speed) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Value())
+}
+
+func (s *Speed) UnmarshalJSON(data []byte) error {
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if s.system == Imperial {
+		s.metersPerSecond = v / 2.23694
+	} else {
+		s.metersPerSecond = v
+	}
+
+	return nil
+}
+
+// Pressure is a barometric pressure value that marshals in its System's
+// units.
+type Pressure struct {
+	hPa    float64
+	system System
+}
+
+// NewPressure builds a Pressure from a canonical hectopascal value.
+func NewPressure(hPa float64, system System) Pressure {
+	return Pressure{hPa: hPa, system: system}
+}
+
+// HPa returns the canonical value, regardless of System.
+func (p Pressure) HPa() float64 { return p.hPa }
+
+// Value returns the pressure converted into p's System.
+func (p Pressure) Value() float64 {
+	if p.system == Imperial {
+		return p.hPa * 0.0295301 // inHg
+	}
+	return p.hPa
+}
+
+func (p Pressure) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Value())
+}
+
+func (p *Pressure) UnmarshalJSON(data []byte) error {
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if p.system == Imperial {
+		p.hPa = v / 0.0295301
+	} else {
+		p.hPa = v
+	}
+
+	return nil
+}